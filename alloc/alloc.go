@@ -0,0 +1,186 @@
+// Package alloc tracks GPU memory reservations so that this agent can
+// participate in fractional-GPU scheduling decisions: a central
+// scheduler asks "how much is free" before placing a workload, and
+// reserves memory on this node ahead of the workload actually starting.
+package alloc
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reservation is a promise that memory_mb of a GPU's memory is set aside
+// for owner until expires_at, whether or not the process has started
+// using it yet.
+type Reservation struct {
+	ID        string    `json:"id"`
+	Owner     string    `json:"owner"`
+	MemoryMB  float64   `json:"memory_mb"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GPUAllocation is the reservation accounting for a single GPU,
+// reconciled against the live memory.used reported by the GPU
+// collector.
+type GPUAllocation struct {
+	GPUID               int           `json:"gpu_id"`
+	AllocatableMemoryMB float64       `json:"allocatable_memory_mb"`
+	AllocatedMemoryMB   float64       `json:"allocated_memory_mb"`
+	Reservations        []Reservation `json:"reservations,omitempty"`
+}
+
+var errNotFound = errors.New("alloc: reservation not found")
+
+// Manager tracks outstanding reservations per GPU. It is safe for
+// concurrent use from the HTTP handlers and the collection loop.
+type Manager struct {
+	mu     sync.Mutex
+	gpus   map[int]*gpuState
+	usedMB map[int]float64
+}
+
+type gpuState struct {
+	allocatableMB float64
+	reservations  map[string]Reservation
+}
+
+// NewManager returns an empty Manager; GPUs are registered lazily the
+// first time SetAllocatable is called for their ID.
+func NewManager() *Manager {
+	return &Manager{gpus: make(map[int]*gpuState), usedMB: make(map[int]float64)}
+}
+
+// Reconcile records the live memory.used reported by the GPU collector
+// for each GPU ID, so that Allocatable reflects actual usage even when
+// it exceeds what was explicitly reserved (e.g. a process allocated
+// memory outside of this accounting).
+func (m *Manager) Reconcile(usedMB map[int]float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.usedMB = usedMB
+}
+
+// SetAllocatable records the total memory available for scheduling on
+// gpuID, typically the GPU's total memory minus a headroom reserved for
+// the driver and other non-scheduled consumers.
+func (m *Manager) SetAllocatable(gpuID int, memoryMB float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.state(gpuID).allocatableMB = memoryMB
+}
+
+// Reserve records a new reservation against gpuID if enough allocatable
+// memory remains, returning the reservation with a generated ID.
+func (m *Manager) Reserve(gpuID int, owner string, memoryMB float64, ttl time.Duration) (Reservation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.gpus[gpuID]
+	if !ok {
+		return Reservation{}, errors.New("alloc: unknown gpu")
+	}
+	m.expireLocked(state)
+
+	if m.allocatedLocked(state)+memoryMB > state.allocatableMB {
+		return Reservation{}, errors.New("alloc: insufficient allocatable memory")
+	}
+
+	r := Reservation{
+		ID:        uuid.New().String(),
+		Owner:     owner,
+		MemoryMB:  memoryMB,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	state.reservations[r.ID] = r
+
+	return r, nil
+}
+
+// Release removes a reservation by ID, searching across all GPUs.
+func (m *Manager) Release(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, state := range m.gpus {
+		if _, ok := state.reservations[id]; ok {
+			delete(state.reservations, id)
+			return nil
+		}
+	}
+
+	return errNotFound
+}
+
+// Allocatable returns the current accounting for every known GPU, sorted
+// by GPUID (and each GPU's reservations sorted by ID) so that repeated
+// reports are stable for the bin-packing server, with expired
+// reservations dropped and allocated memory floored at the last live
+// memory.used reported via Reconcile.
+func (m *Manager) Allocatable() []GPUAllocation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]int, 0, len(m.gpus))
+	for id := range m.gpus {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	out := make([]GPUAllocation, 0, len(ids))
+	for _, id := range ids {
+		state := m.gpus[id]
+		m.expireLocked(state)
+
+		allocated := m.allocatedLocked(state)
+		if live, ok := m.usedMB[id]; ok && live > allocated {
+			allocated = live
+		}
+
+		reservations := make([]Reservation, 0, len(state.reservations))
+		for _, r := range state.reservations {
+			reservations = append(reservations, r)
+		}
+		sort.Slice(reservations, func(i, j int) bool { return reservations[i].ID < reservations[j].ID })
+
+		out = append(out, GPUAllocation{
+			GPUID:               id,
+			AllocatableMemoryMB: state.allocatableMB,
+			AllocatedMemoryMB:   allocated,
+			Reservations:        reservations,
+		})
+	}
+
+	return out
+}
+
+func (m *Manager) state(gpuID int) *gpuState {
+	state, ok := m.gpus[gpuID]
+	if !ok {
+		state = &gpuState{reservations: make(map[string]Reservation)}
+		m.gpus[gpuID] = state
+	}
+	return state
+}
+
+func (m *Manager) allocatedLocked(state *gpuState) float64 {
+	var sum float64
+	for _, r := range state.reservations {
+		sum += r.MemoryMB
+	}
+	return sum
+}
+
+func (m *Manager) expireLocked(state *gpuState) {
+	now := time.Now()
+	for id, r := range state.reservations {
+		if now.After(r.ExpiresAt) {
+			delete(state.reservations, id)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package alloc
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Handler returns the HTTP routes a central scheduler uses to bin-pack
+// workloads across nodes: reserve memory ahead of placing a job, release
+// it once the job lands or is cancelled, and list current headroom.
+func (m *Manager) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/gpu/reserve", m.handleReserve)
+	mux.HandleFunc("/gpu/reserve/", m.handleRelease)
+	mux.HandleFunc("/gpu/allocatable", m.handleAllocatable)
+	return mux
+}
+
+type reserveRequest struct {
+	GPUID    int     `json:"gpu_id"`
+	Owner    string  `json:"owner"`
+	MemoryMB float64 `json:"memory_mb"`
+	TTL      string  `json:"ttl"`
+}
+
+func (m *Manager) handleReserve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req reserveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ttl := 5 * time.Minute
+	if req.TTL != "" {
+		parsed, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			http.Error(w, "invalid ttl", http.StatusBadRequest)
+			return
+		}
+		ttl = parsed
+	}
+
+	reservation, err := m.Reserve(req.GPUID, req.Owner, req.MemoryMB, ttl)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reservation)
+}
+
+func (m *Manager) handleRelease(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/gpu/reserve/")
+	if id == "" {
+		http.Error(w, "missing reservation id", http.StatusBadRequest)
+		return
+	}
+
+	if err := m.Release(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *Manager) handleAllocatable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m.Allocatable())
+}
@@ -0,0 +1,61 @@
+// Package collectors provides a pluggable metric-source architecture,
+// loosely modeled on cc-metric-collector: each metric source (cpu, mem,
+// disk, gpu, ...) implements Collector, and a CollectorManager enables
+// and runs whichever ones a config names, without main needing to know
+// about them.
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Metric is one collector's output for a single collection pass. Value
+// holds the collector's own typed payload (e.g. []stats.CPUInfo) so that
+// callers can merge it back into SystemStats with a type assertion.
+type Metric struct {
+	Collector string      `json:"collector"`
+	Value     interface{} `json:"value"`
+}
+
+// Collector is a single metric source. Init is called once at startup;
+// Collect is called once per collection interval.
+type Collector interface {
+	// Name identifies this collector in emitted Metrics and config.
+	Name() string
+	// Parallel reports whether this collector is safe to run
+	// concurrently with the other enabled collectors.
+	Parallel() bool
+	// Init prepares the collector from its config section, returning an
+	// error if this collector can't run on the current host.
+	Init(cfg json.RawMessage) error
+	// Collect gathers the current sample.
+	Collect(ctx context.Context) ([]Metric, error)
+	// Close releases any resources acquired by Init.
+	Close()
+}
+
+// Config is the top-level collector configuration: a set of enabled
+// collector names mapped to their own (collector-specific) options.
+type Config struct {
+	Collectors map[string]json.RawMessage `json:"collectors"`
+}
+
+// Factory constructs a fresh, uninitialized Collector for a registry
+// entry.
+type Factory func() Collector
+
+// Registry lists every collector implementation available to build a
+// Manager from. Users enable a subset by naming them in Config.
+var Registry = map[string]Factory{
+	"cpu":        newCPUCollector,
+	"memory":     newMemoryCollector,
+	"disk":       newDiskCollector,
+	"network":    newNetworkCollector,
+	"gpu-nvidia": newGPUCollector,
+	"gpu-amd":    newGPUAMDCollector,
+	"gpu-intel":  newGPUIntelCollector,
+	"smart":      newSmartCollector,
+	"ipmi":       newIPMICollector,
+	"containers": newContainersCollector,
+}
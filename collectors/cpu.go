@@ -0,0 +1,53 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/wjzhangq/gpu2/stats"
+)
+
+// cpuCollector reports per-core model/topology once at Init and live
+// usage percentages on every Collect call.
+type cpuCollector struct {
+	infos []stats.CPUInfo
+}
+
+func newCPUCollector() Collector { return &cpuCollector{} }
+
+func (c *cpuCollector) Name() string   { return "cpu" }
+func (c *cpuCollector) Parallel() bool { return true }
+
+func (c *cpuCollector) Init(_ json.RawMessage) error {
+	cpuInfo, err := cpu.Info()
+	if err != nil {
+		return err
+	}
+
+	for i, info := range cpuInfo {
+		c.infos = append(c.infos, stats.CPUInfo{ID: i, Model: info.ModelName, Cores: int(info.Cores)})
+	}
+
+	return nil
+}
+
+func (c *cpuCollector) Collect(ctx context.Context) ([]Metric, error) {
+	percents, err := cpu.PercentWithContext(ctx, 500*time.Millisecond, true)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]stats.CPUInfo, len(c.infos))
+	copy(infos, c.infos)
+	for i := range infos {
+		if i < len(percents) {
+			infos[i].UsagePercent = percents[i]
+		}
+	}
+
+	return []Metric{{Collector: c.Name(), Value: infos}}, nil
+}
+
+func (c *cpuCollector) Close() {}
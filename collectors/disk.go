@@ -0,0 +1,85 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/wjzhangq/gpu2/stats"
+)
+
+var defaultExcludedMounts = []string{"/sys", "/proc", "/dev", "/run", "/snap", "/System"}
+
+// diskCollector reports usage for every partition gopsutil lists with
+// all=true (network and pseudo filesystems included) whose mountpoint
+// doesn't match an excluded prefix.
+type diskCollector struct {
+	exclude []string
+}
+
+type diskConfig struct {
+	Exclude []string `json:"exclude"`
+}
+
+func newDiskCollector() Collector { return &diskCollector{} }
+
+func (c *diskCollector) Name() string   { return "disk" }
+func (c *diskCollector) Parallel() bool { return true }
+
+func (c *diskCollector) Init(cfg json.RawMessage) error {
+	c.exclude = defaultExcludedMounts
+
+	if len(cfg) == 0 {
+		return nil
+	}
+
+	var parsed diskConfig
+	if err := json.Unmarshal(cfg, &parsed); err != nil {
+		return err
+	}
+	if len(parsed.Exclude) > 0 {
+		c.exclude = parsed.Exclude
+	}
+
+	return nil
+}
+
+func (c *diskCollector) Collect(ctx context.Context) ([]Metric, error) {
+	parts, err := disk.PartitionsWithContext(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []stats.DiskInfo
+	for _, p := range parts {
+		if c.excluded(p.Mountpoint) {
+			continue
+		}
+
+		d, err := disk.UsageWithContext(ctx, p.Mountpoint)
+		if err != nil || d.Total < 1e9 { // 排除 total_gb < 1
+			continue
+		}
+
+		infos = append(infos, stats.DiskInfo{
+			Mount:        p.Mountpoint,
+			TotalGB:      float64(d.Total) / 1e9,
+			UsedGB:       float64(d.Used) / 1e9,
+			UsagePercent: d.UsedPercent,
+		})
+	}
+
+	return []Metric{{Collector: c.Name(), Value: infos}}, nil
+}
+
+func (c *diskCollector) Close() {}
+
+func (c *diskCollector) excluded(mount string) bool {
+	for _, ex := range c.exclude {
+		if strings.HasPrefix(mount, ex) {
+			return true
+		}
+	}
+	return false
+}
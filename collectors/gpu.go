@@ -0,0 +1,49 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/wjzhangq/gpu2/gpu"
+)
+
+// gpuCollector wraps the gpu package's NVML/nvidia-smi Collector so it
+// can be enabled or disabled like any other collector.
+type gpuCollector struct {
+	inner gpu.Collector
+}
+
+func newGPUCollector() Collector { return &gpuCollector{} }
+
+func (c *gpuCollector) Name() string   { return "gpu-nvidia" }
+func (c *gpuCollector) Parallel() bool { return true }
+
+// Init never fails: a host with no NVIDIA driver, or one mid-reinstall,
+// should still run the rest of the agent. c.inner stays nil and Collect
+// just reports no devices until a driver shows up.
+func (c *gpuCollector) Init(_ json.RawMessage) error {
+	c.inner = gpu.New()
+	return nil
+}
+
+func (c *gpuCollector) Collect(ctx context.Context) ([]Metric, error) {
+	if c.inner == nil {
+		c.inner = gpu.New()
+	}
+	if c.inner == nil {
+		return []Metric{{Collector: c.Name(), Value: []gpu.Info{}}}, nil
+	}
+
+	infos, err := c.inner.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Metric{{Collector: c.Name(), Value: infos}}, nil
+}
+
+func (c *gpuCollector) Close() {
+	if c.inner != nil {
+		c.inner.Close()
+	}
+}
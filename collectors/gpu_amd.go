@@ -0,0 +1,28 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// gpuAMDCollector is a placeholder for AMD GPU metrics (ROCm SMI). It's
+// registered so a config can name "gpu-amd" without the manager
+// rejecting it as unknown, but Init always fails until a ROCm SMI
+// binding is wired in, so the manager skips it and logs why.
+type gpuAMDCollector struct{}
+
+func newGPUAMDCollector() Collector { return &gpuAMDCollector{} }
+
+func (c *gpuAMDCollector) Name() string   { return "gpu-amd" }
+func (c *gpuAMDCollector) Parallel() bool { return true }
+
+func (c *gpuAMDCollector) Init(_ json.RawMessage) error {
+	return errors.New("gpu-amd: not implemented yet (no ROCm SMI binding)")
+}
+
+func (c *gpuAMDCollector) Collect(_ context.Context) ([]Metric, error) {
+	return nil, errors.New("gpu-amd: not implemented yet")
+}
+
+func (c *gpuAMDCollector) Close() {}
@@ -0,0 +1,28 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// gpuIntelCollector is a placeholder for Intel GPU metrics (Level Zero
+// Sysman). It's registered so a config can name "gpu-intel" without the
+// manager rejecting it as unknown, but Init always fails until a Level
+// Zero binding is wired in, so the manager skips it and logs why.
+type gpuIntelCollector struct{}
+
+func newGPUIntelCollector() Collector { return &gpuIntelCollector{} }
+
+func (c *gpuIntelCollector) Name() string   { return "gpu-intel" }
+func (c *gpuIntelCollector) Parallel() bool { return true }
+
+func (c *gpuIntelCollector) Init(_ json.RawMessage) error {
+	return errors.New("gpu-intel: not implemented yet (no Level Zero Sysman binding)")
+}
+
+func (c *gpuIntelCollector) Collect(_ context.Context) ([]Metric, error) {
+	return nil, errors.New("gpu-intel: not implemented yet")
+}
+
+func (c *gpuIntelCollector) Close() {}
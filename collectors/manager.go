@@ -0,0 +1,96 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Manager runs a config-selected set of Collectors, running the ones
+// that support concurrent collection in parallel and the rest serially
+// in a fixed, deterministic order, then merges all of their output.
+type Manager struct {
+	collectors []Collector
+}
+
+// NewManager builds a Manager from cfg, instantiating and initializing
+// every collector named in cfg.Collectors from registry. An unknown
+// collector name is a configuration error.
+func NewManager(cfg Config, registry map[string]Factory) (*Manager, error) {
+	names := make([]string, 0, len(cfg.Collectors))
+	for name := range cfg.Collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	m := &Manager{}
+	for _, name := range names {
+		factory, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("collectors: unknown collector %q", name)
+		}
+
+		c := factory()
+		if err := c.Init(cfg.Collectors[name]); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: collector %q unavailable, skipping: %v\n", name, err)
+			continue
+		}
+
+		m.collectors = append(m.collectors, c)
+	}
+
+	return m, nil
+}
+
+// Close shuts down every enabled collector, releasing any resources
+// acquired by Init (e.g. an open NVML handle).
+func (m *Manager) Close() {
+	for _, c := range m.collectors {
+		c.Close()
+	}
+}
+
+// Collect runs every enabled collector once and returns their combined
+// metrics. A collector that errors is skipped and logged rather than
+// aborting the whole pass.
+func (m *Manager) Collect(ctx context.Context) []Metric {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		metrics []Metric
+	)
+
+	run := func(c Collector) {
+		ms, err := c.Collect(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: collector %q failed: %v\n", c.Name(), err)
+			return
+		}
+		mu.Lock()
+		metrics = append(metrics, ms...)
+		mu.Unlock()
+	}
+
+	for _, c := range m.collectors {
+		if !c.Parallel() {
+			continue
+		}
+		wg.Add(1)
+		go func(c Collector) {
+			defer wg.Done()
+			run(c)
+		}(c)
+	}
+	wg.Wait()
+
+	for _, c := range m.collectors {
+		if c.Parallel() {
+			continue
+		}
+		run(c)
+	}
+
+	return metrics
+}
@@ -0,0 +1,46 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/wjzhangq/gpu2/stats"
+)
+
+// memoryCollector reports system RAM usage.
+type memoryCollector struct {
+	totalGB float64
+}
+
+func newMemoryCollector() Collector { return &memoryCollector{} }
+
+func (c *memoryCollector) Name() string   { return "memory" }
+func (c *memoryCollector) Parallel() bool { return true }
+
+func (c *memoryCollector) Init(_ json.RawMessage) error {
+	vm, err := mem.VirtualMemory()
+	if err != nil {
+		return err
+	}
+
+	c.totalGB = float64(vm.Total) / 1e9
+	return nil
+}
+
+func (c *memoryCollector) Collect(ctx context.Context) ([]Metric, error) {
+	vm, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := stats.MemoryInfo{
+		TotalGB:      c.totalGB,
+		UsedGB:       float64(vm.Used) / 1e9,
+		UsagePercent: vm.UsedPercent,
+	}
+
+	return []Metric{{Collector: c.Name(), Value: info}}, nil
+}
+
+func (c *memoryCollector) Close() {}
@@ -0,0 +1,82 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/net"
+	"github.com/wjzhangq/gpu2/stats"
+)
+
+var defaultExcludedIfaces = []string{"lo", "docker", "veth", "br-", "virbr"}
+
+// networkCollector reports cumulative traffic counters for every
+// interface whose name doesn't match an excluded prefix.
+type networkCollector struct {
+	exclude []string
+}
+
+type networkConfig struct {
+	Exclude []string `json:"exclude"`
+}
+
+func newNetworkCollector() Collector { return &networkCollector{} }
+
+func (c *networkCollector) Name() string   { return "network" }
+func (c *networkCollector) Parallel() bool { return true }
+
+func (c *networkCollector) Init(cfg json.RawMessage) error {
+	c.exclude = defaultExcludedIfaces
+
+	if len(cfg) == 0 {
+		return nil
+	}
+
+	var parsed networkConfig
+	if err := json.Unmarshal(cfg, &parsed); err != nil {
+		return err
+	}
+	if len(parsed.Exclude) > 0 {
+		c.exclude = parsed.Exclude
+	}
+
+	return nil
+}
+
+func (c *networkCollector) Collect(ctx context.Context) ([]Metric, error) {
+	counters, err := net.IOCountersWithContext(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []stats.NetworkInfo
+	for _, ctr := range counters {
+		if c.excluded(ctr.Name) {
+			continue
+		}
+
+		infos = append(infos, stats.NetworkInfo{
+			Interface:   ctr.Name,
+			BytesSent:   ctr.BytesSent,
+			BytesRecv:   ctr.BytesRecv,
+			PacketsSent: ctr.PacketsSent,
+			PacketsRecv: ctr.PacketsRecv,
+			ErrIn:       ctr.Errin,
+			ErrOut:      ctr.Errout,
+		})
+	}
+
+	return []Metric{{Collector: c.Name(), Value: infos}}, nil
+}
+
+func (c *networkCollector) Close() {}
+
+func (c *networkCollector) excluded(iface string) bool {
+	for _, ex := range c.exclude {
+		if strings.HasPrefix(iface, ex) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// smartCollector is a placeholder for per-disk S.M.A.R.T. health metrics
+// (via smartctl). It's registered so a config can name "smart" without
+// the manager rejecting it as unknown, but Init always fails until the
+// smartctl wrapper is written, so the manager skips it and logs why.
+type smartCollector struct{}
+
+func newSmartCollector() Collector { return &smartCollector{} }
+
+func (c *smartCollector) Name() string   { return "smart" }
+func (c *smartCollector) Parallel() bool { return true }
+
+func (c *smartCollector) Init(_ json.RawMessage) error {
+	return errors.New("smart: not implemented yet (no smartctl wrapper)")
+}
+
+func (c *smartCollector) Collect(_ context.Context) ([]Metric, error) {
+	return nil, errors.New("smart: not implemented yet")
+}
+
+func (c *smartCollector) Close() {}
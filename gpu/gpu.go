@@ -0,0 +1,73 @@
+// Package gpu collects per-device GPU metrics. It prefers the NVML bindings
+// for speed and coverage, falling back to shelling out to nvidia-smi when
+// NVML isn't available (e.g. no NVIDIA driver, or running inside a
+// container without the management library mounted in).
+package gpu
+
+import "context"
+
+// ProcessInfo describes a single process using a GPU, as reported by
+// NVML's per-process accounting.
+type ProcessInfo struct {
+	PID          int32   `json:"pid"`
+	Name         string  `json:"name"`
+	UsedMemoryMB float64 `json:"used_memory_mb"`
+}
+
+// Info is a snapshot of one GPU (or MIG sub-device) at a point in time.
+type Info struct {
+	ID                 int     `json:"id"`
+	ParentID           *int    `json:"parent_id,omitempty"`
+	IsMIGDevice        bool    `json:"is_mig_device,omitempty"`
+	PCIBusID           string  `json:"pci_bus_id,omitempty"`
+	Model              string  `json:"model"`
+	UsagePercent       float64 `json:"usage_percent"`
+	MemoryTotalGB      float64 `json:"memory_total_gb"`
+	MemoryUsedGB       float64 `json:"memory_used_gb"`
+	MemoryUsagePercent float64 `json:"memory_usage_percent"`
+
+	TemperatureC       float64 `json:"temperature_c,omitempty"`
+	PowerDrawW         float64 `json:"power_draw_w,omitempty"`
+	PowerLimitW        float64 `json:"power_limit_w,omitempty"`
+	FanSpeedPercent    float64 `json:"fan_speed_percent,omitempty"`
+	SMClockMHz         int     `json:"sm_clock_mhz,omitempty"`
+	MemClockMHz        int     `json:"mem_clock_mhz,omitempty"`
+	PCIeGeneration     int     `json:"pcie_generation,omitempty"`
+	PCIeWidth          int     `json:"pcie_width,omitempty"`
+	ECCErrorsVolatile  uint64  `json:"ecc_errors_volatile,omitempty"`
+	ECCErrorsAggregate uint64  `json:"ecc_errors_aggregate,omitempty"`
+	EncoderPercent     float64 `json:"encoder_utilization_percent,omitempty"`
+	DecoderPercent     float64 `json:"decoder_utilization_percent,omitempty"`
+
+	Processes []ProcessInfo `json:"processes,omitempty"`
+}
+
+// Collector is a source of GPU metrics. Implementations are not required
+// to be safe for concurrent use; callers should serialize access.
+type Collector interface {
+	// Init prepares the collector, returning an error if this collector
+	// cannot be used on the current host.
+	Init() error
+	// Collect returns the current snapshot for every visible GPU,
+	// including any MIG sub-devices.
+	Collect(ctx context.Context) ([]Info, error)
+	// Close releases any resources acquired by Init.
+	Close()
+}
+
+// New picks the best available Collector for this host: NVML when the
+// NVIDIA Management Library can be loaded, otherwise nvidia-smi. It
+// returns nil if neither is usable.
+func New() Collector {
+	nvml := newNVMLCollector()
+	if err := nvml.Init(); err == nil {
+		return nvml
+	}
+
+	smi := newSMICollector()
+	if err := smi.Init(); err == nil {
+		return smi
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package gpu
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// nvmlCollector reads GPU metrics through the NVIDIA Management Library.
+// It is the preferred Collector: it avoids a subprocess per sample and
+// exposes far more telemetry than the nvidia-smi CSV output.
+type nvmlCollector struct {
+	deviceCount int
+}
+
+func newNVMLCollector() *nvmlCollector {
+	return &nvmlCollector{}
+}
+
+func (c *nvmlCollector) Init() error {
+	if ret := nvml.Init(); ret != nvml.SUCCESS {
+		return fmt.Errorf("nvml: init: %v", nvml.ErrorString(ret))
+	}
+
+	count, ret := nvml.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		nvml.Shutdown()
+		return fmt.Errorf("nvml: device count: %v", nvml.ErrorString(ret))
+	}
+
+	c.deviceCount = count
+	return nil
+}
+
+func (c *nvmlCollector) Close() {
+	nvml.Shutdown()
+}
+
+func (c *nvmlCollector) Collect(ctx context.Context) ([]Info, error) {
+	var infos []Info
+
+	for i := 0; i < c.deviceCount; i++ {
+		dev, ret := nvml.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			continue
+		}
+
+		info := collectDevice(dev, i, nil)
+		infos = append(infos, info)
+
+		migInfos := collectMIGDevices(dev, i)
+		infos = append(infos, migInfos...)
+	}
+
+	return infos, nil
+}
+
+func collectDevice(dev nvml.Device, id int, parentID *int) Info {
+	info := Info{ID: id, ParentID: parentID, IsMIGDevice: parentID != nil}
+
+	if name, ret := dev.GetName(); ret == nvml.SUCCESS {
+		info.Model = name
+	}
+
+	if pci, ret := dev.GetPciInfo(); ret == nvml.SUCCESS {
+		info.PCIBusID = pciBusIDString(pci)
+	}
+
+	if util, ret := dev.GetUtilizationRates(); ret == nvml.SUCCESS {
+		info.UsagePercent = float64(util.Gpu)
+	}
+
+	if mem, ret := dev.GetMemoryInfo(); ret == nvml.SUCCESS {
+		info.MemoryTotalGB = float64(mem.Total) / 1e9
+		info.MemoryUsedGB = float64(mem.Used) / 1e9
+		if mem.Total > 0 {
+			info.MemoryUsagePercent = float64(mem.Used) / float64(mem.Total) * 100
+		}
+	}
+
+	if temp, ret := dev.GetTemperature(nvml.TEMPERATURE_GPU); ret == nvml.SUCCESS {
+		info.TemperatureC = float64(temp)
+	}
+
+	if power, ret := dev.GetPowerUsage(); ret == nvml.SUCCESS {
+		info.PowerDrawW = float64(power) / 1000
+	}
+
+	if limit, ret := dev.GetEnforcedPowerLimit(); ret == nvml.SUCCESS {
+		info.PowerLimitW = float64(limit) / 1000
+	}
+
+	if fan, ret := dev.GetFanSpeed(); ret == nvml.SUCCESS {
+		info.FanSpeedPercent = float64(fan)
+	}
+
+	if clock, ret := dev.GetClockInfo(nvml.CLOCK_SM); ret == nvml.SUCCESS {
+		info.SMClockMHz = int(clock)
+	}
+
+	if clock, ret := dev.GetClockInfo(nvml.CLOCK_MEM); ret == nvml.SUCCESS {
+		info.MemClockMHz = int(clock)
+	}
+
+	if gen, ret := dev.GetCurrPcieLinkGeneration(); ret == nvml.SUCCESS {
+		info.PCIeGeneration = int(gen)
+	}
+
+	if width, ret := dev.GetCurrPcieLinkWidth(); ret == nvml.SUCCESS {
+		info.PCIeWidth = int(width)
+	}
+
+	if volatile, ret := dev.GetTotalEccErrors(nvml.MEMORY_ERROR_TYPE_UNCORRECTED, nvml.VOLATILE_ECC); ret == nvml.SUCCESS {
+		info.ECCErrorsVolatile = volatile
+	}
+
+	if aggregate, ret := dev.GetTotalEccErrors(nvml.MEMORY_ERROR_TYPE_UNCORRECTED, nvml.AGGREGATE_ECC); ret == nvml.SUCCESS {
+		info.ECCErrorsAggregate = aggregate
+	}
+
+	if enc, _, ret := dev.GetEncoderUtilization(); ret == nvml.SUCCESS {
+		info.EncoderPercent = float64(enc)
+	}
+
+	if dec, _, ret := dev.GetDecoderUtilization(); ret == nvml.SUCCESS {
+		info.DecoderPercent = float64(dec)
+	}
+
+	info.Processes = collectProcesses(dev)
+
+	return info
+}
+
+func collectProcesses(dev nvml.Device) []ProcessInfo {
+	procs, ret := dev.GetComputeRunningProcesses()
+	if ret != nvml.SUCCESS {
+		return nil
+	}
+
+	infos := make([]ProcessInfo, 0, len(procs))
+	for _, p := range procs {
+		name, _ := nvml.SystemGetProcessName(int(p.Pid))
+		infos = append(infos, ProcessInfo{
+			PID:          int32(p.Pid),
+			Name:         name,
+			UsedMemoryMB: float64(p.UsedGpuMemory) / 1e6,
+		})
+	}
+
+	return infos
+}
+
+// collectMIGDevices reports each enabled MIG instance on dev as a
+// separate Info entry tagged with ParentID, mirroring how mature GPU
+// exporters expose MIG slices alongside the parent device.
+func collectMIGDevices(dev nvml.Device, parentIndex int) []Info {
+	count, ret := dev.GetMaxMigDeviceCount()
+	if ret != nvml.SUCCESS || count <= 0 {
+		return nil
+	}
+
+	var infos []Info
+	for j := 0; j < count; j++ {
+		migDev, ret := dev.GetMigDeviceHandleByIndex(j)
+		if ret != nvml.SUCCESS {
+			continue
+		}
+
+		id := (parentIndex+1)*1000 + j
+		parent := parentIndex
+		infos = append(infos, collectDevice(migDev, id, &parent))
+	}
+
+	return infos
+}
+
+func pciBusIDString(pci nvml.PciInfo) string {
+	b := make([]byte, 0, len(pci.BusId))
+	for _, c := range pci.BusId {
+		if c == 0 {
+			break
+		}
+		b = append(b, byte(c))
+	}
+	return string(b)
+}
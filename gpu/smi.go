@@ -0,0 +1,97 @@
+package gpu
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// smiCollector shells out to nvidia-smi. It is kept as a fallback for
+// hosts where libnvidia-ml isn't reachable (e.g. NVML headers present but
+// the driver library missing from the container), trading accuracy and
+// speed for compatibility with the previous behavior.
+type smiCollector struct {
+	path string
+}
+
+func newSMICollector() *smiCollector {
+	return &smiCollector{}
+}
+
+func (c *smiCollector) Init() error {
+	c.path = findNvidiaSmi()
+	if c.path == "" {
+		return errors.New("nvidia-smi: not found")
+	}
+	return nil
+}
+
+func (c *smiCollector) Close() {}
+
+func (c *smiCollector) Collect(ctx context.Context) ([]Info, error) {
+	cmd := exec.CommandContext(ctx, c.path,
+		"--query-gpu=name,utilization.gpu,memory.total,memory.used",
+		"--format=csv,noheader,nounits")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	infos := make([]Info, 0, len(lines))
+
+	for i, line := range lines {
+		fields := strings.Split(line, ",")
+		if len(fields) < 4 {
+			continue
+		}
+
+		memTotal := parseFloat(fields[2]) / 1024.0
+		memUsed := parseFloat(fields[3]) / 1024.0
+		memPercent := 0.0
+		if memTotal > 0 {
+			memPercent = (memUsed / memTotal) * 100
+		}
+
+		infos = append(infos, Info{
+			ID:                 i,
+			Model:              strings.TrimSpace(fields[0]),
+			UsagePercent:       parseFloat(fields[1]),
+			MemoryTotalGB:      memTotal,
+			MemoryUsedGB:       memUsed,
+			MemoryUsagePercent: memPercent,
+		})
+	}
+
+	return infos, nil
+}
+
+func findNvidiaSmi() string {
+	if p, err := exec.LookPath("nvidia-smi"); err == nil {
+		return p
+	}
+
+	if runtime.GOOS == "windows" {
+		base := `C:\Windows\System32\DriverStore\FileRepository\`
+		matches, _ := filepath.Glob(base + "nvdm*")
+		for _, m := range matches {
+			exe := filepath.Join(m, "nvidia-smi.exe")
+			if _, err := os.Stat(exe); err == nil {
+				return exe
+			}
+		}
+	}
+
+	return ""
+}
+
+func parseFloat(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return v
+}
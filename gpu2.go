@@ -16,85 +16,86 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
-	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/google/uuid"
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/host"
-	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/wjzhangq/gpu2/alloc"
+	"github.com/wjzhangq/gpu2/collectors"
+	"github.com/wjzhangq/gpu2/gpu"
+	"github.com/wjzhangq/gpu2/machineid"
+	"github.com/wjzhangq/gpu2/remote"
+	"github.com/wjzhangq/gpu2/sinks"
+	"github.com/wjzhangq/gpu2/stats"
 )
 
+// sinkSpecs collects repeated --sink flag values, e.g.
+// --sink prometheus://:9100 --sink influx://token@host:8086/bucket.
+type sinkSpecs []string
+
+func (s *sinkSpecs) String() string { return strings.Join(*s, ",") }
+
+func (s *sinkSpecs) Set(spec string) error {
+	*s = append(*s, spec)
+	return nil
+}
+
 const (
 	REPORT_URL      = "https://gpu.zhangwenjin.com/report"
 	TIMEOUT         = 3 * time.Second
 	COLLECT_TIMEOUT = 5 * time.Second
 )
 
-type OSInfo struct {
-	Name         string `json:"name"`
-	Version      string `json:"version"`
-	Architecture string `json:"architecture"`
-	SMBIOS       string `json:"smbios,omitempty"` // 硬件序列号
-}
-
-type CPUInfo struct {
-	ID           int     `json:"id"`
-	Model        string  `json:"model"`
-	Cores        int     `json:"cores"`
-	UsagePercent float64 `json:"usage_percent"`
-}
-
-type MemoryInfo struct {
-	TotalGB      float64 `json:"total_gb"`
-	UsedGB       float64 `json:"used_gb"`
-	UsagePercent float64 `json:"usage_percent"`
-}
-
-type DiskInfo struct {
-	Mount        string  `json:"mount"`
-	TotalGB      float64 `json:"total_gb"`
-	UsedGB       float64 `json:"used_gb"`
-	UsagePercent float64 `json:"usage_percent"`
+// defaultCollectorConfig enables the built-in collectors when no
+// --config file is given, matching this agent's historical behavior.
+var defaultCollectorConfig = collectors.Config{
+	Collectors: map[string]json.RawMessage{
+		"cpu":        json.RawMessage(`{}`),
+		"memory":     json.RawMessage(`{}`),
+		"disk":       json.RawMessage(`{}`),
+		"gpu-nvidia": json.RawMessage(`{}`),
+	},
 }
 
-type GPUInfo struct {
-	ID                 int     `json:"id"`
-	Model              string  `json:"model"`
-	UsagePercent       float64 `json:"usage_percent"`
-	MemoryTotalGB      float64 `json:"memory_total_gb"`
-	MemoryUsedGB       float64 `json:"memory_used_gb"`
-	MemoryUsagePercent float64 `json:"memory_usage_percent"`
-}
+var (
+	sysInfo      stats.SystemStats
+	collectMgr   *collectors.Manager
+	allocMgr     = alloc.NewManager()
+	sinkDispatch *sinks.Dispatcher
+	mutex        sync.RWMutex
+)
 
-type SystemStats struct {
-	ID       string     `json:"id"`
-	Hostname string     `json:"hostname"`
-	OS       OSInfo     `json:"os"`
-	CPUs     []CPUInfo  `json:"cpus"`
-	Memory   MemoryInfo `json:"memory"`
-	Disks    []DiskInfo `json:"disks"`
-	GPUs     []GPUInfo  `json:"gpus"`
-	TS       int64      `json:"timestamp"`
+// lifecycle lets a long-running host environment (namely the Windows
+// service wrapper) pause and resume the collection loop in place,
+// without tearing down and restarting the process.
+type lifecycle struct {
+	paused atomic.Bool
 }
 
-var (
-	sysInfo       SystemStats
-	nvidiaSmiPath string
-	mutex         sync.RWMutex
-)
+func (l *lifecycle) Pause()         { l.paused.Store(true) }
+func (l *lifecycle) Resume()        { l.paused.Store(false) }
+func (l *lifecycle) IsPaused() bool { return l.paused.Load() }
 
-// 获取或生成 ID (仅内存)
-func getOrCreateID(customID string) string {
+// resolveID picks this host's reported ID: customID if the operator
+// passed one, otherwise a stable ID derived from durable hardware/OS
+// identifiers (falling back to a random one if none are available, in
+// which case the host will report as new after every restart).
+func resolveID(customID, smbios string) (id string, source string) {
 	if customID != "" {
-		return customID
+		return customID, "custom"
 	}
 
-	// 生成新 ID (不保存到文件)
-	return uuid.New().String()
+	id, source, err := machineid.Resolve(smbios)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; falling back to a random ID\n", err)
+		return uuid.New().String(), "random"
+	}
+
+	return id, source
 }
 
 // 获取 SMBIOS 信息 (硬件序列号)
@@ -131,230 +132,115 @@ func getSMBIOS() string {
 	return ""
 }
 
-func findNvidiaSmi() string {
-	if p, err := exec.LookPath("nvidia-smi"); err == nil {
-		return p
+// loadCollectorConfig reads a collectors.Config from configPath, or
+// returns defaultCollectorConfig if configPath is empty. Both JSON and
+// TOML are accepted, selected by the file's extension.
+func loadCollectorConfig(configPath string) (collectors.Config, error) {
+	if configPath == "" {
+		return defaultCollectorConfig, nil
 	}
-	if runtime.GOOS == "windows" {
-		base := `C:\Windows\System32\DriverStore\FileRepository\`
-		matches, _ := filepath.Glob(base + "nvdm*")
-		for _, m := range matches {
-			exe := filepath.Join(m, "nvidia-smi.exe")
-			if _, err := os.Stat(exe); err == nil {
-				return exe
-			}
-		}
-	}
-	return ""
-}
-
-func initStaticInfo() {
-	sysInfo.Hostname, _ = os.Hostname()
 
-	// OS 信息
-	hi, _ := host.Info()
-	sysInfo.OS = OSInfo{
-		Name:         hi.Platform + " " + hi.PlatformVersion,
-		Version:      hi.KernelVersion,
-		Architecture: runtime.GOARCH,
-		SMBIOS:       getSMBIOS(),
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return collectors.Config{}, err
 	}
 
-	// CPU 信息 - 带超时保护
-	done := make(chan bool, 1)
-	go func() {
-		cpuInfo, _ := cpu.Info()
-		for i, c := range cpuInfo {
-			sysInfo.CPUs = append(sysInfo.CPUs, CPUInfo{
-				ID:    i,
-				Model: c.ModelName,
-				Cores: int(c.Cores),
-			})
-		}
-		done <- true
-	}()
-
-	select {
-	case <-done:
-	case <-time.After(COLLECT_TIMEOUT):
-		fmt.Fprintln(os.Stderr, "Warning: CPU info collection timeout")
+	if strings.EqualFold(filepath.Ext(configPath), ".toml") {
+		return parseTOMLCollectorConfig(data)
 	}
 
-	// Memory 总量
-	if vm, err := mem.VirtualMemory(); err == nil {
-		sysInfo.Memory.TotalGB = float64(vm.Total) / 1e9
+	var cfg collectors.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return collectors.Config{}, err
 	}
 
-	// Disks - 带超时保护
-	go func() {
-		parts, _ := disk.Partitions(true)
-		var disks []DiskInfo
-		excludedMounts := []string{"/sys", "/proc", "/dev", "/run", "/snap", "/System"}
-		for _, p := range parts {
-			d, err := disk.Usage(p.Mountpoint)
-			if err == nil && d.Total >= 1e9 { // 排除 total_gb < 1
-				exclude := false
-				for _, ex := range excludedMounts {
-					if strings.HasPrefix(p.Mountpoint, ex) {
-						exclude = true
-						break
-					}
-				}
-				if !exclude {
-					disks = append(disks, DiskInfo{
-						Mount:   p.Mountpoint,
-						TotalGB: float64(d.Total) / 1e9,
-					})
-				}
-			}
-		}
-		mutex.Lock()
-		sysInfo.Disks = disks
-		mutex.Unlock()
-		done <- true
-	}()
+	return cfg, nil
+}
 
-	select {
-	case <-done:
-	case <-time.After(COLLECT_TIMEOUT):
-		fmt.Fprintln(os.Stderr, "Warning: Disk info collection timeout")
+// parseTOMLCollectorConfig decodes a TOML collector config of the form
+//
+//	[collectors.disk]
+//	exclude = ["/mnt/backup"]
+//
+// into a collectors.Config, re-encoding each collector's own options as
+// JSON since that's what Collector.Init expects.
+func parseTOMLCollectorConfig(data []byte) (collectors.Config, error) {
+	var raw struct {
+		Collectors map[string]map[string]interface{} `toml:"collectors"`
 	}
-
-	// GPU 静态信息
-	nvidiaSmiPath = findNvidiaSmi()
-	if nvidiaSmiPath != "" {
-		updateGPU(true)
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return collectors.Config{}, err
 	}
-}
-
-// 并发更新动态信息
-func updateDynamicInfo() {
-	var wg sync.WaitGroup
-	wg.Add(4)
-
-	// CPU 使用率
-	go func() {
-		defer wg.Done()
-		done := make(chan []float64, 1)
-		go func() {
-			p, _ := cpu.Percent(500*time.Millisecond, true)
-			done <- p
-		}()
-
-		select {
-		case p := <-done:
-			mutex.Lock()
-			for i := range sysInfo.CPUs {
-				if i < len(p) {
-					sysInfo.CPUs[i].UsagePercent = p[i]
-				}
-			}
-			mutex.Unlock()
-		case <-time.After(COLLECT_TIMEOUT):
-			fmt.Fprintln(os.Stderr, "Warning: CPU usage timeout")
-		}
-	}()
-
-	// 内存使用
-	go func() {
-		defer wg.Done()
-		if vm, err := mem.VirtualMemory(); err == nil {
-			mutex.Lock()
-			sysInfo.Memory.UsedGB = float64(vm.Used) / 1e9
-			sysInfo.Memory.UsagePercent = vm.UsedPercent
-			mutex.Unlock()
-		}
-	}()
-
-	// 磁盘使用
-	go func() {
-		defer wg.Done()
-		mutex.RLock()
-		disks := make([]DiskInfo, len(sysInfo.Disks))
-		copy(disks, sysInfo.Disks)
-		mutex.RUnlock()
 
-		for i := range disks {
-			if d, err := disk.Usage(disks[i].Mount); err == nil {
-				disks[i].UsedGB = float64(d.Used) / 1e9
-				disks[i].UsagePercent = d.UsedPercent
-			}
+	cfg := collectors.Config{Collectors: make(map[string]json.RawMessage, len(raw.Collectors))}
+	for name, opts := range raw.Collectors {
+		b, err := json.Marshal(opts)
+		if err != nil {
+			return collectors.Config{}, err
 		}
+		cfg.Collectors[name] = b
+	}
 
-		mutex.Lock()
-		sysInfo.Disks = disks
-		mutex.Unlock()
-	}()
-
-	// GPU 使用
-	go func() {
-		defer wg.Done()
-		updateGPU(false)
-	}()
-
-	wg.Wait()
+	return cfg, nil
 }
 
-func updateGPU(isInit bool) {
-	if nvidiaSmiPath == "" {
-		return
+func initStaticInfo(smbios string) {
+	sysInfo.Hostname, _ = os.Hostname()
+
+	// OS 信息
+	hi, _ := host.Info()
+	sysInfo.OS = stats.OSInfo{
+		Name:         hi.Platform + " " + hi.PlatformVersion,
+		Version:      hi.KernelVersion,
+		Architecture: runtime.GOARCH,
+		SMBIOS:       smbios,
 	}
+}
 
+// collectAndMerge runs every enabled collector once and folds the
+// results into sysInfo, reconciling GPU allocation accounting against
+// whatever the gpu-nvidia collector reported.
+func collectAndMerge() {
 	ctx, cancel := context.WithTimeout(context.Background(), COLLECT_TIMEOUT)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, nvidiaSmiPath,
-		"--query-gpu=name,utilization.gpu,memory.total,memory.used",
-		"--format=csv,noheader,nounits")
-
-	out, err := cmd.Output()
-	if err != nil {
-		return
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	metrics := collectMgr.Collect(ctx)
 
 	mutex.Lock()
 	defer mutex.Unlock()
 
-	if isInit {
-		sysInfo.GPUs = []GPUInfo{}
+	for _, m := range metrics {
+		switch m.Collector {
+		case "cpu":
+			sysInfo.CPUs, _ = m.Value.([]stats.CPUInfo)
+		case "memory":
+			sysInfo.Memory, _ = m.Value.(stats.MemoryInfo)
+		case "disk":
+			sysInfo.Disks, _ = m.Value.([]stats.DiskInfo)
+		case "network":
+			sysInfo.Networks, _ = m.Value.([]stats.NetworkInfo)
+		case "gpu-nvidia":
+			infos, _ := m.Value.([]gpu.Info)
+			sysInfo.GPUs = infos
+			sysInfo.GPUAllocations = reconcileAllocations(infos)
+		}
 	}
+}
 
-	for i, line := range lines {
-		fields := strings.Split(line, ",")
-		if len(fields) < 4 {
+// reconcileAllocations updates allocMgr with the GPUs' current total and
+// used memory and returns the refreshed per-GPU accounting.
+func reconcileAllocations(infos []gpu.Info) []alloc.GPUAllocation {
+	usedMB := make(map[int]float64, len(infos))
+	for _, info := range infos {
+		if info.IsMIGDevice {
 			continue
 		}
-
-		model := strings.TrimSpace(fields[0])
-		usage := parseFloat(fields[1])
-		memTotal := parseFloat(fields[2]) / 1024.0
-		memUsed := parseFloat(fields[3]) / 1024.0
-		memPercent := 0.0
-		if memTotal > 0 {
-			memPercent = (memUsed / memTotal) * 100
-		}
-
-		if isInit {
-			sysInfo.GPUs = append(sysInfo.GPUs, GPUInfo{
-				ID:            i,
-				Model:         model,
-				MemoryTotalGB: memTotal,
-			})
-		}
-
-		if i < len(sysInfo.GPUs) {
-			sysInfo.GPUs[i].UsagePercent = usage
-			sysInfo.GPUs[i].MemoryUsedGB = memUsed
-			sysInfo.GPUs[i].MemoryUsagePercent = memPercent
-		}
+		allocMgr.SetAllocatable(info.ID, info.MemoryTotalGB*1000)
+		usedMB[info.ID] = info.MemoryUsedGB * 1000
 	}
-}
+	allocMgr.Reconcile(usedMB)
 
-func parseFloat(s string) float64 {
-	v, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
-	return v
+	return allocMgr.Allocatable()
 }
 
 func report(REPORT_URL string) {
@@ -387,50 +273,172 @@ func report(REPORT_URL string) {
 	_, _ = io.ReadAll(resp.Body) // 消费响应体
 }
 
+// reportBatch POSTs a batch of remote-tagged stats to REPORT_URL, as
+// collected by an aggregator node.
+func reportBatch(reportURL string, batch []remote.TaggedStats) {
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("POST", reportURL, bytes.NewBuffer(data))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT)
+	defer cancel()
+	req = req.WithContext(ctx)
+
+	client := &http.Client{Timeout: TIMEOUT}
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Batch report failed: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.ReadAll(resp.Body)
+}
+
+// runAggregator scrapes every configured remote on each tick and
+// forwards the combined batch to reportURL. Used for monitoring
+// air-gapped subnets where only this node has outbound internet access.
+func runAggregator(interval time.Duration, reportURL string, remotes []string) {
+	aggregator := remote.NewAggregator(remotes, COLLECT_TIMEOUT)
+
+	collectAndForward := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), COLLECT_TIMEOUT)
+		defer cancel()
+
+		batch := aggregator.Collect(ctx)
+		fmt.Printf("Aggregated %d/%d remotes\n", len(batch), len(remotes))
+		reportBatch(reportURL, batch)
+	}
+
+	collectAndForward()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		collectAndForward()
+	}
+}
+
 func main() {
 	interval := flag.Duration("interval", 5*time.Second, "collect interval")
 	customID := flag.String("id", "", "custom system ID (auto-generated if not provided)")
 	reportURL := flag.String("url", "https://gpu.zhangwenjin.com/report", "report URL")
+	allocAddr := flag.String("alloc-addr", "", "address to serve the GPU allocation API on (e.g. :9400); disabled if empty")
+	configPath := flag.String("config", "", "path to a collectors config (JSON, or TOML if the file ends in .toml); built-in cpu/memory/disk/gpu-nvidia collectors are used if empty")
+	serveAddr := flag.String("serve", "", "address to publish this node's SystemStats on for a remote aggregator to scrape (e.g. :1234); disabled if empty")
+	remotes := flag.String("remotes", "", "comma-separated host:port list of --serve agents to scrape and forward as a batch; runs this node as an aggregator instead of collecting locally")
+	var sinkFlags sinkSpecs
+	flag.Var(&sinkFlags, "sink", "metrics sink to push/expose each sample on (e.g. prometheus://:9100); repeatable")
 	flag.Parse()
 
-	// 初始化 ID
-	sysInfo.ID = getOrCreateID(*customID)
+	if *remotes != "" {
+		runAggregator(*interval, *reportURL, strings.Split(*remotes, ","))
+		return
+	}
 
-	// 初始化静态信息
-	fmt.Println("Initializing system info...")
-	initStaticInfo()
+	// 初始化 ID: 优先复用跨重启持久化的稳定 ID
+	smbios := getSMBIOS()
+	sysInfo.ID, sysInfo.IDSource = resolveID(*customID, smbios)
 
-	// 首次更新动态信息
-	updateDynamicInfo()
-	sysInfo.TS = time.Now().Unix()
+	cfg, err := loadCollectorConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load collector config: %v\n", err)
+		os.Exit(1)
+	}
 
-	// 打印首次信息
-	mutex.RLock()
-	data, _ := json.MarshalIndent(sysInfo, "", "  ")
-	mutex.RUnlock()
-	fmt.Println(string(data))
+	collectMgr, err = collectors.NewManager(cfg, collectors.Registry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize collectors: %v\n", err)
+		os.Exit(1)
+	}
+	defer collectMgr.Close()
 
-	// 首次上报
-	go report(*reportURL)
+	if *allocAddr != "" {
+		go func() {
+			if err := http.ListenAndServe(*allocAddr, allocMgr.Handler()); err != nil {
+				fmt.Fprintf(os.Stderr, "GPU allocation API stopped: %v\n", err)
+			}
+		}()
+	}
 
-	// 定期采集和上报
-	ticker := time.NewTicker(*interval)
-	defer ticker.Stop()
+	if *serveAddr != "" {
+		go func() {
+			getStats := func() stats.SystemStats {
+				mutex.RLock()
+				defer mutex.RUnlock()
+				return sysInfo
+			}
+			if err := http.ListenAndServe(*serveAddr, remote.Handler(getStats)); err != nil {
+				fmt.Fprintf(os.Stderr, "Stats server stopped: %v\n", err)
+			}
+		}()
+	}
 
-	for range ticker.C {
-		updateDynamicInfo()
+	var activeSinks []sinks.Sink
+	for _, spec := range sinkFlags {
+		sink, err := sinks.Parse(spec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to configure sink %q: %v\n", spec, err)
+			os.Exit(1)
+		}
+		activeSinks = append(activeSinks, sink)
+	}
+	sinkDispatch = sinks.NewDispatcher(activeSinks, COLLECT_TIMEOUT)
+	defer sinkDispatch.Close()
+
+	// 初始化静态信息
+	fmt.Println("Initializing system info...")
+	initStaticInfo(smbios)
+
+	runPlatform(func(ctx context.Context, lc *lifecycle) {
+		runAgent(ctx, lc, *interval, *reportURL)
+	})
+}
+
+// runAgent collects and reports on every tick until ctx is cancelled,
+// skipping a tick entirely while lc is paused. On non-Windows hosts lc
+// is never paused; on Windows it's driven by svc.Pause/svc.Continue so
+// the service can halt collection without exiting.
+func runAgent(ctx context.Context, lc *lifecycle, interval time.Duration, reportURL string) {
+	collectAndReport := func() {
+		if lc.IsPaused() {
+			return
+		}
+
+		collectAndMerge()
 
 		mutex.Lock()
 		sysInfo.TS = time.Now().Unix()
 		mutex.Unlock()
 
-		// 打印更新后的信息
 		mutex.RLock()
 		data, _ := json.MarshalIndent(sysInfo, "", "  ")
+		snapshot := sysInfo
 		mutex.RUnlock()
 		fmt.Println(string(data))
 
-		// 异步上报
-		go report(*reportURL)
+		go report(reportURL)
+		sinkDispatch.Write(snapshot)
+	}
+
+	collectAndReport()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			collectAndReport()
+		}
 	}
 }
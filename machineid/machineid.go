@@ -0,0 +1,144 @@
+// Package machineid derives a stable identifier for the current host by
+// hashing together whatever durable hardware/OS identifiers are
+// available, so the same machine reports the same ID across restarts
+// instead of a fresh UUID every time.
+package machineid
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// namespace is a fixed UUID namespace so that identical inputs always
+// derive the same UUIDv5, independent of when or where it's computed.
+var namespace = uuid.MustParse("6f2b8d1e-6c1b-4e9b-9e2b-6b1e7b2d9c3e")
+
+// Resolve returns a stable ID for the current host and a label
+// describing which sources it was derived from (e.g. "smbios+mac"), so
+// the server can tell a strongly-derived ID from a weak one.
+//
+// A previously cached result is preferred over recomputing, so that a
+// host keeps reporting the same ID even if one of its sources
+// (typically the MAC, on a host with multiple NICs and DHCP re-ordering)
+// later changes. Resolve falls back through smbios, the primary MAC
+// address, and the OS machine-id in that order, combining whichever are
+// available; it only errors if none of them are.
+func Resolve(smbios string) (id string, source string, err error) {
+	if cachedID, cachedSource, err := readCache(); err == nil && cachedID != "" {
+		return cachedID, cachedSource, nil
+	}
+
+	var parts, labels []string
+
+	if smbios != "" {
+		parts = append(parts, smbios)
+		labels = append(labels, "smbios")
+	}
+	if mac := primaryMAC(); mac != "" {
+		parts = append(parts, mac)
+		labels = append(labels, "mac")
+	}
+	if mid := systemMachineID(); mid != "" {
+		parts = append(parts, mid)
+		labels = append(labels, "machine-id")
+	}
+
+	if len(parts) == 0 {
+		return "", "", errors.New("machineid: no stable source available on this host")
+	}
+
+	id = uuid.NewSHA1(namespace, []byte(strings.Join(parts, "|"))).String()
+	source = strings.Join(labels, "+")
+
+	// Caching is best-effort: worst case we just recompute next start,
+	// which yields the same ID and source only if the sources are unchanged.
+	_ = writeCache(id, source)
+
+	return id, source, nil
+}
+
+func cachePath() string {
+	if runtime.GOOS == "windows" {
+		base := os.Getenv("PROGRAMDATA")
+		if base == "" {
+			base = `C:\ProgramData`
+		}
+		return filepath.Join(base, "GPUAgent", "id")
+	}
+	return "/var/lib/gpu-agent/id"
+}
+
+// readCache returns the cached ID and the source label it was derived
+// from. Caches written before the source was persisted alongside the ID
+// only have an ID line; those report as source "cache" rather than
+// replaying a label that was never recorded.
+func readCache() (id string, source string, err error) {
+	data, err := os.ReadFile(cachePath())
+	if err != nil {
+		return "", "", err
+	}
+
+	lines := strings.SplitN(strings.TrimSpace(string(data)), "\n", 2)
+	if len(lines) < 2 || lines[1] == "" {
+		return lines[0], "cache", nil
+	}
+	return lines[0], lines[1], nil
+}
+
+func writeCache(id, source string) error {
+	path := cachePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(id+"\n"+source), 0o644)
+}
+
+// virtualIfacePrefixes names interfaces created by container/VM
+// networking and VPN software, which come and go independently of the
+// host's physical identity and make a weak basis for a stable ID.
+var virtualIfacePrefixes = []string{
+	"docker", "veth", "br-", "virbr", "vmnet", "vboxnet", "tun", "tap", "wg", "zt",
+}
+
+func isVirtualIface(name string) bool {
+	for _, prefix := range virtualIfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryMAC returns the hardware address of the first non-virtual,
+// non-loopback interface that has one, preferring a physical NIC over a
+// container/VM bridge so the derived ID doesn't depend on whatever
+// network stack happens to be running. It falls back to any interface
+// with a hardware address if no physical one is found.
+func primaryMAC() string {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+
+	var fallback string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 {
+			continue
+		}
+		if fallback == "" {
+			fallback = iface.HardwareAddr.String()
+		}
+		if isVirtualIface(iface.Name) {
+			continue
+		}
+		return iface.HardwareAddr.String()
+	}
+
+	return fallback
+}
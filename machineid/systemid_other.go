@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package machineid
+
+import (
+	"os"
+	"strings"
+)
+
+// systemMachineID reads the OS-assigned machine ID, generated at
+// install time and stable across reboots.
+func systemMachineID() string {
+	data, err := os.ReadFile("/etc/machine-id")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
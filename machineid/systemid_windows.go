@@ -0,0 +1,23 @@
+//go:build windows
+// +build windows
+
+package machineid
+
+import "golang.org/x/sys/windows/registry"
+
+// systemMachineID reads MachineGuid, generated at install time and
+// stable across reboots.
+func systemMachineID() string {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Cryptography`, registry.QUERY_VALUE|registry.WOW64_64KEY)
+	if err != nil {
+		return ""
+	}
+	defer k.Close()
+
+	guid, _, err := k.GetStringValue("MachineGuid")
+	if err != nil {
+		return ""
+	}
+
+	return guid
+}
@@ -0,0 +1,119 @@
+// Package remote lets one agent publish its SystemStats over HTTP and
+// lets another agent pull and combine stats from a set of such peers.
+// This supports monitoring air-gapped subnets where only one node has
+// outbound internet access: every other node runs with --serve, and
+// that one node runs with --remotes to scrape and forward them all.
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/wjzhangq/gpu2/stats"
+)
+
+// StatsPath is the endpoint a --serve agent exposes its latest
+// SystemStats on, and the one an aggregator fetches from each remote.
+const StatsPath = "/stats"
+
+// TaggedStats is a remote's SystemStats annotated with the host it came
+// from, so a central server can tell samples in an aggregated batch
+// apart.
+type TaggedStats struct {
+	stats.SystemStats
+	SourceHost string `json:"source_host"`
+}
+
+// Handler serves the latest stats returned by getStats on StatsPath, for
+// an aggregator to scrape.
+func Handler(getStats func() stats.SystemStats) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(StatsPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(getStats())
+	})
+	return mux
+}
+
+// Aggregator periodically pulls SystemStats from a fixed set of remote
+// agents.
+type Aggregator struct {
+	remotes []string
+	client  *http.Client
+}
+
+// NewAggregator returns an Aggregator that scrapes each of remotes
+// (host:port, without a scheme or path) within timeout per request.
+func NewAggregator(remotes []string, timeout time.Duration) *Aggregator {
+	return &Aggregator{
+		remotes: remotes,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Collect fetches every remote concurrently and returns the ones that
+// answered successfully, each tagged with the host it came from. A
+// remote that errors or times out is skipped rather than failing the
+// whole batch.
+func (a *Aggregator) Collect(ctx context.Context) []TaggedStats {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		batch []TaggedStats
+	)
+
+	for _, host := range a.remotes {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+
+			s, err := a.fetch(ctx, host)
+			if err != nil {
+				fmt.Printf("Warning: failed to scrape remote %s: %v\n", host, err)
+				return
+			}
+
+			mu.Lock()
+			batch = append(batch, TaggedStats{SystemStats: s, SourceHost: host})
+			mu.Unlock()
+		}(host)
+	}
+	wg.Wait()
+
+	return batch
+}
+
+func (a *Aggregator) fetch(ctx context.Context, host string) (stats.SystemStats, error) {
+	url := fmt.Sprintf("http://%s%s", host, StatsPath)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return stats.SystemStats{}, err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return stats.SystemStats{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return stats.SystemStats{}, fmt.Errorf("remote %s: unexpected status %s", host, resp.Status)
+	}
+
+	var s stats.SystemStats
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return stats.SystemStats{}, err
+	}
+
+	return s, nil
+}
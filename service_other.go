@@ -0,0 +1,12 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import "context"
+
+// runPlatform runs agentMain directly; there's no service manager to
+// defer to outside Windows, so lifecycle is never paused.
+func runPlatform(agentMain func(ctx context.Context, lc *lifecycle)) {
+	agentMain(context.Background(), &lifecycle{})
+}
@@ -4,36 +4,174 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
 
 	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
 )
 
-type appService struct{}
+const serviceName = "GPUService"
+
+// runPlatform dispatches to the Windows Service Control Manager when
+// running as an installed service, runs agentMain directly when invoked
+// as a normal program, and handles the install/uninstall subcommands
+// used to register the service and its event source.
+func runPlatform(agentMain func(ctx context.Context, lc *lifecycle)) {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "install":
+			if err := installService(); err != nil {
+				fmt.Fprintf(os.Stderr, "install failed: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("service installed")
+			return
+		case "uninstall":
+			if err := uninstallService(); err != nil {
+				fmt.Fprintf(os.Stderr, "uninstall failed: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("service uninstalled")
+			return
+		}
+	}
+
+	is, err := svc.IsWindowsService()
+	if err == nil && is {
+		runAsService(agentMain)
+		return
+	}
+
+	log.Println("Running as normal program in Windows")
+	agentMain(context.Background(), &lifecycle{})
+}
+
+// appService adapts agentMain to the svc.Handler interface, translating
+// SCM lifecycle events into context cancellation and lifecycle
+// pause/resume, and mirroring them to the Windows Event Log.
+type appService struct {
+	agentMain func(ctx context.Context, lc *lifecycle)
+	elog      *eventlog.Log
+}
+
+func runAsService(agentMain func(ctx context.Context, lc *lifecycle)) {
+	elog, err := eventlog.Open(serviceName)
+	if err != nil {
+		log.Printf("Warning: could not open event log: %v", err)
+		elog = nil
+	}
+	if elog != nil {
+		defer elog.Close()
+	}
+
+	if err := svc.Run(serviceName, &appService{agentMain: agentMain, elog: elog}); err != nil {
+		logEvent(elog, fmt.Sprintf("service failed: %v", err))
+	}
+}
 
 func (s *appService) Execute(args []string, r <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown | svc.AcceptPauseAndContinue
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lc := &lifecycle{}
+
 	status <- svc.Status{State: svc.StartPending}
-	go main_func()
-	status <- svc.Status{State: svc.Running}
+	go s.agentMain(ctx, lc)
+	status <- svc.Status{State: svc.Running, Accepts: accepted}
+	logEvent(s.elog, "GPUService started")
 
 	for c := range r {
 		switch c.Cmd {
+		case svc.Interrogate:
+			status <- c.CurrentStatus
 		case svc.Stop, svc.Shutdown:
 			status <- svc.Status{State: svc.StopPending}
+			logEvent(s.elog, "GPUService stopping")
+			cancel()
 			return false, 0
+		case svc.Pause:
+			lc.Pause()
+			status <- svc.Status{State: svc.Paused, Accepts: accepted}
+			logEvent(s.elog, "GPUService paused")
+		case svc.Continue:
+			lc.Resume()
+			status <- svc.Status{State: svc.Running, Accepts: accepted}
+			logEvent(s.elog, "GPUService resumed")
 		}
 	}
+
 	return false, 0
 }
 
-func tryRunAsWindowsService() {
-	is, err := svc.IsWindowsService()
-	if err == nil && is {
-		log.Println("Running as Windows service...")
-		svc.Run("GPUService", &appService{})
+// logEvent writes an informational event, tolerating a nil elog (e.g.
+// the event source failed to open) by falling back to the log package.
+func logEvent(elog *eventlog.Log, msg string) {
+	if elog == nil {
+		log.Println(msg)
 		return
 	}
+	if err := elog.Info(1, msg); err != nil {
+		log.Printf("failed to write event log entry %q: %v", msg, err)
+	}
+}
+
+// installService registers the GPUService event source and creates the
+// Windows service pointing at the current executable.
+func installService() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("resolve executable path: %w", err)
+	}
 
-	log.Println("Running as normal program in Windows")
-	main_func()
+	if err := eventlog.InstallAsEventCreate(serviceName, eventlog.Info|eventlog.Warning|eventlog.Error); err != nil {
+		return fmt.Errorf("register event source: %w", err)
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connect to service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.CreateService(serviceName, exe, mgr.Config{
+		DisplayName: "GPU Metrics Agent",
+		Description: "Collects and reports GPU, CPU, memory, and disk metrics.",
+		StartType:   mgr.StartAutomatic,
+	})
+	if err != nil {
+		return fmt.Errorf("create service: %w", err)
+	}
+	defer s.Close()
+
+	return nil
+}
+
+// uninstallService removes the GPUService service and its event source.
+func uninstallService() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connect to service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return fmt.Errorf("open service: %w", err)
+	}
+	defer s.Close()
+
+	if err := s.Delete(); err != nil {
+		return fmt.Errorf("delete service: %w", err)
+	}
+
+	if err := eventlog.Remove(serviceName); err != nil {
+		return fmt.Errorf("remove event source: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,85 @@
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wjzhangq/gpu2/stats"
+)
+
+// influxSink pushes each sample as InfluxDB v2 line protocol via the
+// HTTP write API.
+type influxSink struct {
+	writeURL string
+	token    string
+	client   *http.Client
+}
+
+func newInfluxSink(u *url.URL) (*influxSink, error) {
+	bucket := strings.TrimPrefix(u.Path, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("sinks: influx sink requires a bucket path, got %q", u.String())
+	}
+
+	token := ""
+	if u.User != nil {
+		token = u.User.Username()
+	}
+
+	writeURL := fmt.Sprintf("http://%s/api/v2/write?org=%s&bucket=%s&precision=ns",
+		u.Host, url.QueryEscape(u.Query().Get("org")), url.QueryEscape(bucket))
+
+	return &influxSink{writeURL: writeURL, token: token, client: &http.Client{}}, nil
+}
+
+func (s *influxSink) Write(ctx context.Context, stat stats.SystemStats) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.writeURL, strings.NewReader(lineProtocol(stat)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Token "+s.token)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sinks: influx write failed: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (s *influxSink) Close() error { return nil }
+
+func lineProtocol(stat stats.SystemStats) string {
+	ts := time.Unix(stat.TS, 0).UnixNano()
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "memory,host=%s,id=%s usage_percent=%f,used_gb=%f %d\n",
+		stat.Hostname, stat.ID, stat.Memory.UsagePercent, stat.Memory.UsedGB, ts)
+
+	for _, c := range stat.CPUs {
+		fmt.Fprintf(&b, "cpu,host=%s,id=%s,cpu_id=%d usage_percent=%f %d\n",
+			stat.Hostname, stat.ID, c.ID, c.UsagePercent, ts)
+	}
+
+	for _, d := range stat.Disks {
+		fmt.Fprintf(&b, "disk,host=%s,id=%s,mount=%s usage_percent=%f,used_gb=%f %d\n",
+			stat.Hostname, stat.ID, d.Mount, d.UsagePercent, d.UsedGB, ts)
+	}
+
+	for _, g := range stat.GPUs {
+		fmt.Fprintf(&b, "gpu,host=%s,id=%s,gpu_id=%d usage_percent=%f,memory_usage_percent=%f %d\n",
+			stat.Hostname, stat.ID, g.ID, g.UsagePercent, g.MemoryUsagePercent, ts)
+	}
+
+	return b.String()
+}
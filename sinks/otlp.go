@@ -0,0 +1,131 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/wjzhangq/gpu2/stats"
+)
+
+// otlpSink pushes gauges to an OTLP/HTTP metrics receiver as an
+// ExportMetricsServiceRequest JSON payload.
+type otlpSink struct {
+	endpoint string
+	client   *http.Client
+}
+
+func newOTLPSink(host string) (*otlpSink, error) {
+	return &otlpSink{
+		endpoint: fmt.Sprintf("http://%s/v1/metrics", host),
+		client:   &http.Client{},
+	}, nil
+}
+
+type otlpAttribute struct {
+	Key   string        `json:"key"`
+	Value otlpAttrValue `json:"value"`
+}
+
+type otlpAttrValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpDataPoint struct {
+	TimeUnixNano string          `json:"timeUnixNano"`
+	AsDouble     float64         `json:"asDouble"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpMetric struct {
+	Name  string    `json:"name"`
+	Gauge otlpGauge `json:"gauge"`
+}
+
+type otlpScopeMetrics struct {
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+func otlpAttr(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpAttrValue{StringValue: value}}
+}
+
+func otlpPoint(ts int64, value float64, attrs ...otlpAttribute) otlpDataPoint {
+	return otlpDataPoint{
+		TimeUnixNano: fmt.Sprintf("%d", ts),
+		AsDouble:     value,
+		Attributes:   attrs,
+	}
+}
+
+func (s *otlpSink) Write(ctx context.Context, stat stats.SystemStats) error {
+	ts := time.Unix(stat.TS, 0).UnixNano()
+	hostAttrs := []otlpAttribute{otlpAttr("host", stat.Hostname), otlpAttr("id", stat.ID)}
+
+	metrics := []otlpMetric{
+		{
+			Name:  "gpu2.memory.usage_percent",
+			Gauge: otlpGauge{DataPoints: []otlpDataPoint{otlpPoint(ts, stat.Memory.UsagePercent, hostAttrs...)}},
+		},
+	}
+
+	for _, g := range stat.GPUs {
+		attrs := append(append([]otlpAttribute{}, hostAttrs...), otlpAttr("gpu_id", fmt.Sprintf("%d", g.ID)))
+		metrics = append(metrics, otlpMetric{
+			Name:  "gpu2.gpu.usage_percent",
+			Gauge: otlpGauge{DataPoints: []otlpDataPoint{otlpPoint(ts, g.UsagePercent, attrs...)}},
+		})
+	}
+
+	req := otlpRequest{ResourceMetrics: []otlpResourceMetrics{
+		{
+			Resource:     otlpResource{Attributes: hostAttrs},
+			ScopeMetrics: []otlpScopeMetrics{{Metrics: metrics}},
+		},
+	}}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sinks: otlp write failed: %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (s *otlpSink) Close() error { return nil }
@@ -0,0 +1,74 @@
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/wjzhangq/gpu2/stats"
+)
+
+// prometheusSink exposes the latest sample as Prometheus gauges on
+// /metrics, labeled with host, id, and gpu_id/mount where applicable.
+type prometheusSink struct {
+	mu     sync.RWMutex
+	latest stats.SystemStats
+}
+
+func newPrometheusSink(addr string) (*prometheusSink, error) {
+	s := &prometheusSink{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.serveMetrics)
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			fmt.Printf("Warning: prometheus sink stopped: %v\n", err)
+		}
+	}()
+
+	return s, nil
+}
+
+func (s *prometheusSink) Write(_ context.Context, stat stats.SystemStats) error {
+	s.mu.Lock()
+	s.latest = stat
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *prometheusSink) Close() error { return nil }
+
+func (s *prometheusSink) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	stat := s.latest
+	s.mu.RUnlock()
+
+	var b strings.Builder
+	labels := fmt.Sprintf(`host=%q,id=%q`, stat.Hostname, stat.ID)
+
+	fmt.Fprintf(&b, "# TYPE gpu2_memory_usage_percent gauge\n")
+	fmt.Fprintf(&b, "gpu2_memory_usage_percent{%s} %f\n", labels, stat.Memory.UsagePercent)
+
+	fmt.Fprintf(&b, "# TYPE gpu2_cpu_usage_percent gauge\n")
+	for _, c := range stat.CPUs {
+		fmt.Fprintf(&b, "gpu2_cpu_usage_percent{%s,cpu_id=\"%d\"} %f\n", labels, c.ID, c.UsagePercent)
+	}
+
+	fmt.Fprintf(&b, "# TYPE gpu2_disk_usage_percent gauge\n")
+	for _, d := range stat.Disks {
+		fmt.Fprintf(&b, "gpu2_disk_usage_percent{%s,mount=%q} %f\n", labels, d.Mount, d.UsagePercent)
+	}
+
+	fmt.Fprintf(&b, "# TYPE gpu2_gpu_usage_percent gauge\n")
+	fmt.Fprintf(&b, "# TYPE gpu2_gpu_memory_usage_percent gauge\n")
+	for _, g := range stat.GPUs {
+		fmt.Fprintf(&b, "gpu2_gpu_usage_percent{%s,gpu_id=\"%d\"} %f\n", labels, g.ID, g.UsagePercent)
+		fmt.Fprintf(&b, "gpu2_gpu_memory_usage_percent{%s,gpu_id=\"%d\"} %f\n", labels, g.ID, g.MemoryUsagePercent)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write([]byte(b.String()))
+}
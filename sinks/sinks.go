@@ -0,0 +1,79 @@
+// Package sinks delivers each collected sample to one or more metrics
+// backends. report() POSTs a single JSON blob to a central server; a
+// Sink is a second, independent delivery path selected per-run via
+// --sink, so an operator's existing Prometheus/InfluxDB/OTLP stack can
+// scrape or receive this agent's data directly.
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/wjzhangq/gpu2/stats"
+)
+
+// Sink receives each collected sample and forwards or exposes it
+// however its backend expects.
+type Sink interface {
+	Write(ctx context.Context, s stats.SystemStats) error
+	Close() error
+}
+
+// Parse builds a Sink from a spec in the form scheme://...:
+//
+//	prometheus://:9100                        serve a /metrics scrape endpoint on :9100
+//	influx://token@host:8086/bucket?org=myorg  push line protocol to an InfluxDB v2 bucket
+//	otlp://host:4318                           push OTLP/HTTP metrics
+func Parse(spec string) (Sink, error) {
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("sinks: invalid sink %q: %w", spec, err)
+	}
+
+	switch u.Scheme {
+	case "prometheus":
+		return newPrometheusSink(u.Host)
+	case "influx":
+		return newInfluxSink(u)
+	case "otlp":
+		return newOTLPSink(u.Host)
+	default:
+		return nil, fmt.Errorf("sinks: unknown sink scheme %q", u.Scheme)
+	}
+}
+
+// Dispatcher fans a sample out to every configured sink concurrently,
+// each bounded by its own timeout, so one slow sink can't hold up the
+// others or the collection loop.
+type Dispatcher struct {
+	sinks   []Sink
+	timeout time.Duration
+}
+
+// NewDispatcher returns a Dispatcher for sinks, giving each Write call
+// up to timeout to complete.
+func NewDispatcher(sinks []Sink, timeout time.Duration) *Dispatcher {
+	return &Dispatcher{sinks: sinks, timeout: timeout}
+}
+
+// Write dispatches s to every sink without blocking the caller.
+func (d *Dispatcher) Write(s stats.SystemStats) {
+	for _, sink := range d.sinks {
+		go func(sink Sink) {
+			ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+			defer cancel()
+			if err := sink.Write(ctx, s); err != nil {
+				fmt.Printf("Warning: sink write failed: %v\n", err)
+			}
+		}(sink)
+	}
+}
+
+// Close shuts down every sink.
+func (d *Dispatcher) Close() {
+	for _, sink := range d.sinks {
+		sink.Close()
+	}
+}
@@ -0,0 +1,65 @@
+// Package stats holds the shared payload types reported by this agent.
+// They live in their own package so that both the collectors package
+// and main can depend on them without a cycle.
+package stats
+
+import (
+	"github.com/wjzhangq/gpu2/alloc"
+	"github.com/wjzhangq/gpu2/gpu"
+)
+
+type OSInfo struct {
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Architecture string `json:"architecture"`
+	SMBIOS       string `json:"smbios,omitempty"` // 硬件序列号
+}
+
+type CPUInfo struct {
+	ID           int     `json:"id"`
+	Model        string  `json:"model"`
+	Cores        int     `json:"cores"`
+	UsagePercent float64 `json:"usage_percent"`
+}
+
+type MemoryInfo struct {
+	TotalGB      float64 `json:"total_gb"`
+	UsedGB       float64 `json:"used_gb"`
+	UsagePercent float64 `json:"usage_percent"`
+}
+
+type DiskInfo struct {
+	Mount        string  `json:"mount"`
+	TotalGB      float64 `json:"total_gb"`
+	UsedGB       float64 `json:"used_gb"`
+	UsagePercent float64 `json:"usage_percent"`
+}
+
+// NetworkInfo is the cumulative traffic counters for one network
+// interface since boot, as reported by the OS.
+type NetworkInfo struct {
+	Interface   string `json:"interface"`
+	BytesSent   uint64 `json:"bytes_sent"`
+	BytesRecv   uint64 `json:"bytes_recv"`
+	PacketsSent uint64 `json:"packets_sent"`
+	PacketsRecv uint64 `json:"packets_recv"`
+	ErrIn       uint64 `json:"errors_in,omitempty"`
+	ErrOut      uint64 `json:"errors_out,omitempty"`
+}
+
+// SystemStats is the full payload reported to REPORT_URL. It's assembled
+// from whatever collectors are enabled, so any field may be left at its
+// zero value if the corresponding collector isn't configured.
+type SystemStats struct {
+	ID             string                `json:"id"`
+	IDSource       string                `json:"id_source,omitempty"`
+	Hostname       string                `json:"hostname"`
+	OS             OSInfo                `json:"os"`
+	CPUs           []CPUInfo             `json:"cpus,omitempty"`
+	Memory         MemoryInfo            `json:"memory"`
+	Disks          []DiskInfo            `json:"disks,omitempty"`
+	Networks       []NetworkInfo         `json:"networks,omitempty"`
+	GPUs           []gpu.Info            `json:"gpus,omitempty"`
+	GPUAllocations []alloc.GPUAllocation `json:"gpu_allocations,omitempty"`
+	TS             int64                 `json:"timestamp"`
+}